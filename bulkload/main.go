@@ -0,0 +1,169 @@
+// bulkload concurrently loads a large N-Quad file into Dgraph. dgo has no
+// batch-mutation mode of its own, so this hand-rolls one: a pool of workers
+// each running its own Txn, fed N-Quads over a channel, committing every
+// -size lines. It is meant as a template for high-volume ingest, something
+// the single-Req example in client01.go does not cover.
+//
+// Usage:
+//
+//	bulkload -r rdf.nq -d 127.0.0.1:9080 -c 100 -size 1000
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/dgraph-io/dgo"
+	"github.com/dgraph-io/dgo/protos/api"
+)
+
+var (
+	dgraph  = flag.String("d", "127.0.0.1:9080", "Dgraph server address")
+	rdfFile = flag.String("r", "", "File containing N-Quads to load")
+	workers = flag.Int("c", 100, "Number of concurrent worker transactions")
+	size    = flag.Int("size", 1000, "Number of N-Quads to commit per transaction")
+)
+
+// counters tracks ingest progress across all workers.
+type counters struct {
+	nquads   int64
+	txnsDone int64
+}
+
+func main() {
+	flag.Parse()
+	if *rdfFile == "" {
+		log.Fatal("Please specify a file containing N-Quads with -r")
+	}
+
+	conn, err := grpc.Dial(*dgraph, grpc.WithInsecure())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+
+	dg := dgo.NewDgraphClient(api.NewDgraphClient(conn))
+
+	// readCtx only governs how long we keep feeding new lines into the
+	// channel; it is canceled on SIGINT. It must never be the context workers
+	// use for their own Mutate/Commit calls, or an in-flight commit would be
+	// aborted by the same signal that's supposed to flush it cleanly.
+	readCtx, stopReading := context.WithCancel(context.Background())
+	defer stopReading()
+	commitCtx := context.Background()
+
+	f, err := os.Open(*rdfFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	lines := make(chan string, *workers)
+	var c counters
+	var wg sync.WaitGroup
+
+	for i := 0; i < *workers; i++ {
+		wg.Add(1)
+		go worker(commitCtx, dg, lines, *size, &c, &wg)
+	}
+
+	// Stop reading new lines on SIGINT, but let workers finish committing
+	// whatever they already have so the flush on exit is a real flush.
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	go func() {
+		<-sigCh
+		log.Println("Caught interrupt, draining in-flight batches...")
+		stopReading()
+	}()
+
+	start := time.Now()
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				printProgress(&c, start)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	scanner := bufio.NewScanner(f)
+scan:
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		select {
+		case lines <- line:
+		case <-readCtx.Done():
+			break scan
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+
+	close(lines)
+	wg.Wait()
+	close(done)
+
+	printProgress(&c, start)
+}
+
+func worker(ctx context.Context, dg *dgo.Dgraph, lines <-chan string, batchSize int, c *counters, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	var batch []string
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		nquads := strings.Join(batch, "\n")
+		txn := dg.NewTxn()
+		if _, err := txn.Mutate(ctx, &api.Mutation{SetNquads: []byte(nquads)}); err != nil {
+			log.Printf("Error in batch mutation: %s", err)
+			txn.Discard(ctx)
+			batch = batch[:0]
+			return
+		}
+		if err := txn.Commit(ctx); err != nil {
+			log.Printf("Error committing batch: %s", err)
+		} else {
+			atomic.AddInt64(&c.nquads, int64(len(batch)))
+			atomic.AddInt64(&c.txnsDone, 1)
+		}
+		batch = batch[:0]
+	}
+
+	for line := range lines {
+		batch = append(batch, line)
+		if len(batch) >= batchSize {
+			flush()
+		}
+	}
+	flush()
+}
+
+func printProgress(c *counters, start time.Time) {
+	elapsed := time.Since(start)
+	nquads := atomic.LoadInt64(&c.nquads)
+	txns := atomic.LoadInt64(&c.txnsDone)
+	log.Printf("Edges: %d, txns: %d, elapsed: %s, rate: %.0f edges/sec\n",
+		nquads, txns, elapsed, float64(nquads)/elapsed.Seconds())
+}
@@ -7,6 +7,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
@@ -14,131 +15,129 @@ import (
 
 	"google.golang.org/grpc"
 
-	"github.com/dgraph-io/dgraph/client"
-	"github.com/dgraph-io/dgraph/protos"
-	"github.com/gogo/protobuf/proto"
-	"github.com/twpayne/go-geom/encoding/wkb"
+	"github.com/dgraph-io/dgo"
+	"github.com/dgraph-io/dgo/protos/api"
 )
 
 var (
-	dgraph = flag.String("d", "127.0.0.1:8080", "Dgraph server address")
+	dgraph = flag.String("d", "127.0.0.1:9080", "Dgraph server address")
 )
 
-func main() {
-	conn, err := grpc.Dial(*dgraph, grpc.WithInsecure())
-
-	c := protos.NewDgraphClient(conn)
-	req := client.Req{}
-
-	// _:person1 tells Dgraph to assign a new Uid and is the preferred way of creating new nodes.
-	// See https://docs.dgraph.io/master/query-language/#assigning-uid for more details.
-	nq := protos.NQuad{
-		Subject:   "_:person1",
-		Predicate: "name",
-	}
-	client.Str("Steven Spielberg", &nq)
-
-	if err := client.AddFacet("since", "2006-01-02T15:04:05", &nq); err != nil {
-		log.Fatal(err)
-	}
-
-	// To add a facet of type string, use a raw string literal with "" like below or if
-	// you are using an interpreted string literal then you'd need to add and escape the
-	// double quotes like client.AddFacet("alias","\"Steve\"", &nq)
-	if err := client.AddFacet("alias", `"Steve"`, &nq); err != nil {
-		log.Fatal(err)
-	}
-
-	req.AddMutation(nq, client.SET)
+// Loc is the nested-object form Dgraph's JSON mutation format requires for
+// a `geo` predicate; a bare GeoJSON string would be stored as a string
+// value instead of being indexed as geo data.
+type Loc struct {
+	Type        string    `json:"type,omitempty"`
+	Coordinates []float64 `json:"coordinates,omitempty"`
+}
 
-	nq = protos.NQuad{
-		Subject:   "_:person1",
-		Predicate: "now",
-	}
-	if err = client.Datetime(time.Now(), &nq); err != nil {
-		log.Fatal(err)
-	}
-	req.AddMutation(nq, client.SET)
+// Person maps onto a "Person" node in Dgraph. The `friend|since` and
+// `friend|close` style keys let us set facets on the "friend" edge inline
+// with the mutation.
+type Person struct {
+	Uid     string     `json:"uid,omitempty"`
+	Name    string     `json:"name,omitempty"`
+	Now     *time.Time `json:"now,omitempty"`
+	Bday    *time.Time `json:"birthday,omitempty"`
+	Loc     *Loc       `json:"loc,omitempty"`
+	Age     int        `json:"age,omitempty"`
+	Salary  float64    `json:"salary,omitempty"`
+	Married bool       `json:"married,omitempty"`
+	Friend  []*Person  `json:"friend,omitempty"`
+	DType   []string   `json:"dgraph.type,omitempty"`
+
+	// Facets on the "friend" edge are expressed as predicate|facet keys.
+	FriendSince string `json:"friend|since,omitempty"`
+	FriendClose string `json:"friend|close,omitempty"`
+}
 
-	nq = protos.NQuad{
-		Subject:   "_:person1",
-		Predicate: "birthday",
-	}
-	if err = client.Date(time.Date(1991, 2, 1, 0, 0, 0, 0, time.UTC), &nq); err != nil {
+func main() {
+	conn, err := grpc.Dial(*dgraph, grpc.WithInsecure())
+	if err != nil {
 		log.Fatal(err)
 	}
-	req.AddMutation(nq, client.SET)
-
-	nq = protos.NQuad{
-		Subject:   "_:person1",
-		Predicate: "loc",
+	defer conn.Close()
+
+	dg := dgo.NewDgraphClient(api.NewDgraphClient(conn))
+	ctx := context.Background()
+
+	op := &api.Operation{
+		Schema: `
+			name: string @index(exact) .
+			now: datetime .
+			birthday: datetime .
+			loc: geo @index(geo) .
+			age: int .
+			salary: float .
+			married: bool .
+			friend: [uid] .
+			type Person {
+				name: string
+				now: datetime
+				birthday: datetime
+				loc: geo
+				age: int
+				salary: float
+				married: bool
+				friend: [Person]
+			}
+		`,
 	}
-	if err = client.ValueFromGeoJson(`{"type":"Point","coordinates":[-122.2207184,37.72129059]}`, &nq); err != nil {
+	if err := dg.Alter(ctx, op); err != nil {
 		log.Fatal(err)
 	}
-	req.AddMutation(nq, client.SET)
 
-	nq = protos.NQuad{
-		Subject:   "_:person1",
-		Predicate: "age",
-	}
-	if err = client.Int(25, &nq); err != nil {
+	now := time.Now()
+	bday := time.Date(1991, 2, 1, 0, 0, 0, 0, time.UTC)
+	person1 := &Person{
+		Uid:         "_:person1",
+		Name:        "Steven Spielberg",
+		Now:         &now,
+		Bday:        &bday,
+		Loc:         &Loc{Type: "Point", Coordinates: []float64{-122.2207184, 37.72129059}},
+		Age:         25,
+		Salary:      13333.6161,
+		Married:     false,
+		DType:       []string{"Person"},
+		Friend: []*Person{
+			{
+				Uid:         "_:person2",
+				Name:        "William Jones",
+				DType:       []string{"Person"},
+				FriendSince: "2006-01-02T15:04:05",
+				FriendClose: "true",
+			},
+		},
+	}
+
+	pb, err := json.Marshal(person1)
+	if err != nil {
 		log.Fatal(err)
 	}
-	req.AddMutation(nq, client.SET)
 
-	nq = protos.NQuad{
-		Subject:   "_:person1",
-		Predicate: "salary",
-	}
-	if err = client.Float(13333.6161, &nq); err != nil {
-		log.Fatal(err)
-	}
-	req.AddMutation(nq, client.SET)
+	txn := dg.NewTxn()
+	defer txn.Discard(ctx)
 
-	nq = protos.NQuad{
-		Subject:   "_:person1",
-		Predicate: "married",
+	mu := &api.Mutation{SetJson: pb}
+	assigned, err := txn.Mutate(ctx, mu)
+	if err != nil {
+		log.Fatalf("Error in getting response from server, %s", err)
 	}
-	if err = client.Bool(false, &nq); err != nil {
+	if err := txn.Commit(ctx); err != nil {
 		log.Fatal(err)
 	}
-	req.AddMutation(nq, client.SET)
-
-	nq = protos.NQuad{
-		Subject:   "_:person2",
-		Predicate: "name",
-	}
-	client.Str("William Jones", &nq)
-	req.AddMutation(nq, client.SET)
 
-	// Lets connect the two nodes together.
-	nq = protos.NQuad{
-		Subject:   "_:person1",
-		Predicate: "friend",
-		ObjectId:  "_:person2",
-	}
-
-	if err := client.AddFacet("close", "true", &nq); err != nil {
-		log.Fatal(err)
-	}
+	person1Uid := assigned.Uids["person1"]
+	person2Uid := assigned.Uids["person2"]
 
-	req.AddMutation(nq, client.SET)
-	// Lets run the request with all these mutations.
-	resp, err := c.Run(context.Background(), req.Request())
-	if err != nil {
-		log.Fatalf("Error in getting response from server, %s", err)
-	}
-	person1Uid := resp.AssignedUids["person1"]
-	person2Uid := resp.AssignedUids["person2"]
+	// Lets initiate a new transaction and query for the data.
+	txn = dg.NewTxn()
+	defer txn.Discard(ctx)
 
-	// Lets initiate a new request and query for the data.
-	req = client.Req{}
-	// Lets set the starting node id to person1Uid.
-	req.SetQuery(fmt.Sprintf(`{
-		me(id: %v) {
-			_uid_
-			name @facets
+	q := fmt.Sprintf(`{
+		me(func: uid(%s)) {
+			uid
+			name
 			now
 			birthday
 			loc
@@ -146,60 +145,67 @@ func main() {
 			age
 			married
 			friend @facets {
-				_uid_
+				uid
 				name
 			}
 		}
-	}`, client.Uid(person1Uid)))
-	resp, err = c.Run(context.Background(), req.Request())
+	}`, person1Uid)
+
+	resp, err := txn.Query(ctx, q)
 	if err != nil {
 		log.Fatalf("Error in getting response from server, %s", err)
 	}
 
-	fmt.Printf("Raw Response: %+v\n", proto.MarshalTextString(resp))
+	fmt.Printf("Raw Response: %s\n", resp.Json)
 
-	person1 := resp.N[0].Children[0]
-	props := person1.Properties
-	name := props[0].Value.GetStrVal()
-	fmt.Println("Name: ", name)
+	var out struct {
+		Me []Person `json:"me"`
+	}
+	if err := json.Unmarshal(resp.Json, &out); err != nil {
+		log.Fatal(err)
+	}
+	if len(out.Me) == 0 {
+		log.Fatal("expected at least one person back")
+	}
+	person1Read := out.Me[0]
 
-	// We use time.Parse for Date and Datetime values, to get the actual value back.
-	now, err := time.Parse(time.RFC3339, props[1].Value.GetStrVal())
-	if err != nil {
-		log.Fatalf("Error in parsing time, %s", err)
+	fmt.Println("Name: ", person1Read.Name)
+	if person1Read.Now != nil {
+		fmt.Println("Now: ", *person1Read.Now)
+	}
+	if person1Read.Bday != nil {
+		fmt.Println("Birthday: ", *person1Read.Bday)
 	}
-	fmt.Println("Now: ", now)
+	if person1Read.Loc != nil {
+		fmt.Println("Loc: ", person1Read.Loc.Type, person1Read.Loc.Coordinates)
+	}
+	fmt.Println("Salary: ", person1Read.Salary)
+	fmt.Println("Age: ", person1Read.Age)
+	fmt.Println("Married: ", person1Read.Married)
 
-	birthday, err := time.Parse(time.RFC3339, props[2].Value.GetStrVal())
-	if err != nil {
-		log.Fatalf("Error in parsing time, %s", err)
+	if len(person1Read.Friend) > 0 {
+		person2Read := person1Read.Friend[0]
+		fmt.Printf("friend name: %v\n", person2Read.Name)
 	}
-	fmt.Println("Birthday: ", birthday)
 
-	// We use wkb.Unmarshal to get the geom object back from Geo val.
-	geom, err := wkb.Unmarshal(props[3].Value.GetGeoVal())
+	// Deleting an edge.
+	del := &Person{
+		Uid: person1Uid,
+		Friend: []*Person{
+			{Uid: person2Uid},
+		},
+	}
+	db, err := json.Marshal(del)
 	if err != nil {
 		log.Fatal(err)
 	}
-	fmt.Println("Loc: ", geom)
-
-	fmt.Println("Salary: ", props[4].Value.GetDoubleVal())
-	fmt.Println("Age: ", props[5].Value.GetIntVal())
-	fmt.Println("Married: ", props[6].Value.GetBoolVal())
 
-	person2 := person1.Children[0]
-	fmt.Printf("%v name: %v\n", person2.Attribute, person2.Properties[0].Value.GetStrVal())
-
-	// Deleting an edge.
-	nq = protos.NQuad{
-		Subject:   client.Uid(person1Uid),
-		Predicate: "friend",
-		ObjectId:  client.Uid(person2Uid),
-	}
-	req = client.Req{}
-	req.AddMutation(nq, client.DEL)
-	resp, err = c.Run(context.Background(), req.Request())
-	if err != nil {
+	txn = dg.NewTxn()
+	defer txn.Discard(ctx)
+	if _, err := txn.Mutate(ctx, &api.Mutation{DeleteJson: db}); err != nil {
 		log.Fatalf("Error in getting response from server, %s", err)
 	}
+	if err := txn.Commit(ctx); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -0,0 +1,173 @@
+// geoexample stores Points, Polygons and MultiPolygons in Dgraph and runs
+// the four spatial query functions (near, within, contains, intersects)
+// against them. The single Point in client01.go never exercises any of
+// this, so this is a standalone program covering the rest of Dgraph's geo
+// support.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+
+	"google.golang.org/grpc"
+
+	"github.com/dgraph-io/dgo"
+	"github.com/dgraph-io/dgo/protos/api"
+)
+
+var (
+	dgraph = flag.String("d", "127.0.0.1:9080", "Dgraph server address")
+)
+
+// Geo is the nested-object form Dgraph's JSON mutation format requires for
+// a `geo` predicate. Coordinates is left as interface{} since Point,
+// Polygon and MultiPolygon each nest to a different depth.
+type Geo struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+// Place maps onto a node carrying a name and a geo predicate.
+type Place struct {
+	Uid  string `json:"uid,omitempty"`
+	Name string `json:"name,omitempty"`
+	Loc  *Geo   `json:"loc,omitempty"`
+}
+
+func main() {
+	flag.Parse()
+
+	conn, err := grpc.Dial(*dgraph, grpc.WithInsecure())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+
+	dg := dgo.NewDgraphClient(api.NewDgraphClient(conn))
+	ctx := context.Background()
+
+	op := &api.Operation{
+		Schema: `
+			name: string @index(exact) .
+			loc: geo @index(geo) .
+		`,
+	}
+	if err := dg.Alter(ctx, op); err != nil {
+		log.Fatal(err)
+	}
+
+	places := []*Place{
+		{
+			Uid:  "_:sfoffice",
+			Name: "San Francisco office",
+			Loc:  &Geo{Type: "Point", Coordinates: []float64{-122.4194, 37.7749}},
+		},
+		{
+			Uid:  "_:sfneighborhood",
+			Name: "SF neighborhood",
+			Loc: &Geo{
+				Type: "Polygon",
+				Coordinates: [][][]float64{{
+					{-122.42, 37.77}, {-122.40, 37.77},
+					{-122.40, 37.79}, {-122.42, 37.79},
+					{-122.42, 37.77},
+				}},
+			},
+		},
+		{
+			Uid:  "_:bayarea",
+			Name: "Bay Area",
+			Loc: &Geo{
+				Type: "MultiPolygon",
+				Coordinates: [][][][]float64{
+					{{{-122.42, 37.77}, {-122.40, 37.77}, {-122.40, 37.79}, {-122.42, 37.79}, {-122.42, 37.77}}},
+					{{{-122.28, 37.80}, {-122.25, 37.80}, {-122.25, 37.83}, {-122.28, 37.83}, {-122.28, 37.80}}},
+				},
+			},
+		},
+	}
+
+	pb, err := json.Marshal(places)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	txn := dg.NewTxn()
+	defer txn.Discard(ctx)
+	assigned, err := txn.Mutate(ctx, &api.Mutation{SetJson: pb})
+	if err != nil {
+		log.Fatalf("Error in getting response from server, %s", err)
+	}
+	if err := txn.Commit(ctx); err != nil {
+		log.Fatal(err)
+	}
+
+	sfNeighborhoodUid := assigned.Uids["sfneighborhood"]
+	bayAreaUid := assigned.Uids["bayarea"]
+
+	runGeoQuery(ctx, dg, "near", `{
+		me(func: near(loc, [-122.4194,37.7749], 5000)) {
+			uid
+			name
+			loc
+		}
+	}`)
+
+	runGeoQuery(ctx, dg, "within", fmt.Sprintf(`{
+		me(func: within(loc, [[-122.43,37.76],[-122.39,37.76],[-122.39,37.80],[-122.43,37.80],[-122.43,37.76]])) @filter(uid(%s)) {
+			uid
+			name
+			loc
+		}
+	}`, sfNeighborhoodUid))
+
+	runGeoQuery(ctx, dg, "contains", fmt.Sprintf(`{
+		me(func: contains(loc, [-122.4194,37.7749])) @filter(uid(%s)) {
+			uid
+			name
+			loc
+		}
+	}`, sfNeighborhoodUid))
+
+	runGeoQuery(ctx, dg, "intersects", fmt.Sprintf(`{
+		me(func: intersects(loc, [[-122.41,37.78],[-122.39,37.78],[-122.39,37.81],[-122.41,37.81],[-122.41,37.78]])) @filter(uid(%s)) {
+			uid
+			name
+			loc
+		}
+	}`, bayAreaUid))
+}
+
+func runGeoQuery(ctx context.Context, dg *dgo.Dgraph, label, query string) {
+	txn := dg.NewReadOnlyTxn()
+	defer txn.Discard(ctx)
+
+	resp, err := txn.Query(ctx, query)
+	if err != nil {
+		log.Fatalf("Error running %s query: %s", label, err)
+	}
+
+	fmt.Printf("--- %s ---\n", label)
+	fmt.Printf("Raw Response: %s\n", resp.Json)
+
+	var out struct {
+		Me []Place `json:"me"`
+	}
+	if err := json.Unmarshal(resp.Json, &out); err != nil {
+		log.Fatal(err)
+	}
+	if len(out.Me) == 0 {
+		fmt.Println("no matches")
+		return
+	}
+	for _, p := range out.Me {
+		locJSON, err := json.Marshal(p.Loc)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%s: %s\n", p.Name, locJSON)
+	}
+}
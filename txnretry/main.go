@@ -0,0 +1,124 @@
+// txnretry demonstrates Dgraph's serializable transactions: a read-modify-
+// write loop that retries on conflict. The scenario here transfers a
+// "friend" edge from one person to another, which only commits cleanly if
+// nobody else touched either node in the meantime.
+//
+// Usage:
+//
+//	txnretry -d 127.0.0.1:9080 -from <uid> -to <uid> -friend <uid>
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+
+	"google.golang.org/grpc"
+
+	"github.com/dgraph-io/dgo"
+	"github.com/dgraph-io/dgo/protos/api"
+)
+
+var (
+	dgraph = flag.String("d", "127.0.0.1:9080", "Dgraph server address")
+	from   = flag.String("from", "", "Uid of the person losing the friend edge")
+	to     = flag.String("to", "", "Uid of the person gaining the friend edge")
+	friend = flag.String("friend", "", "Uid of the friend being transferred")
+)
+
+type person struct {
+	Uid    string    `json:"uid"`
+	Friend []*person `json:"friend,omitempty"`
+}
+
+const maxRetries = 10
+
+func main() {
+	flag.Parse()
+	if *from == "" || *to == "" || *friend == "" {
+		log.Fatal("Please specify -from, -to and -friend uids")
+	}
+
+	conn, err := grpc.Dial(*dgraph, grpc.WithInsecure())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+
+	dg := dgo.NewDgraphClient(api.NewDgraphClient(conn))
+
+	if err := transferFriend(context.Background(), dg, *from, *to, *friend); err != nil {
+		log.Fatalf("giving up after retries: %s", err)
+	}
+	log.Println("transfer committed")
+}
+
+// transferFriend moves the "friend" edge identified by friendUid from
+// fromUid to toUid inside a single serializable transaction, retrying the
+// whole read-query-mutate-commit cycle whenever Dgraph reports a conflicting
+// concurrent transaction.
+func transferFriend(ctx context.Context, dg *dgo.Dgraph, fromUid, toUid, friendUid string) error {
+	var err error
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		if err = tryTransfer(ctx, dg, fromUid, toUid, friendUid); err == nil {
+			return nil
+		}
+		if err != dgo.ErrAborted {
+			return err
+		}
+		log.Printf("transaction aborted, retrying (attempt %d)", attempt+1)
+	}
+	return err
+}
+
+func tryTransfer(ctx context.Context, dg *dgo.Dgraph, fromUid, toUid, friendUid string) error {
+	txn := dg.NewTxn()
+	defer txn.Discard(ctx)
+
+	q := `query q($id: string, $fid: string) {
+		p(func: uid($id)) {
+			uid
+			friend @filter(uid($fid)) {
+				uid
+			}
+		}
+	}`
+	vars := map[string]string{"$id": fromUid, "$fid": friendUid}
+	resp, err := txn.QueryWithVars(ctx, q, vars)
+	if err != nil {
+		return err
+	}
+
+	var out struct {
+		P []person `json:"p"`
+	}
+	if err := json.Unmarshal(resp.Json, &out); err != nil {
+		return err
+	}
+	if len(out.P) == 0 || len(out.P[0].Friend) == 0 {
+		return nil // already transferred, or edge never existed.
+	}
+
+	del := person{Uid: fromUid, Friend: []*person{{Uid: friendUid}}}
+	delJSON, err := json.Marshal(del)
+	if err != nil {
+		return err
+	}
+	set := person{Uid: toUid, Friend: []*person{{Uid: friendUid}}}
+	setJSON, err := json.Marshal(set)
+	if err != nil {
+		return err
+	}
+
+	mu := &api.Mutation{DeleteJson: delJSON}
+	if _, err := txn.Mutate(ctx, mu); err != nil {
+		return err
+	}
+	mu = &api.Mutation{SetJson: setJSON}
+	if _, err := txn.Mutate(ctx, mu); err != nil {
+		return err
+	}
+
+	return txn.Commit(ctx)
+}